@@ -0,0 +1,153 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package exec provides a client for running commands in, and forwarding
+// ports to, containers in a Kubernetes CAAS model.
+package exec
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/juju/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/tools/remotecommand"
+	"k8s.io/client-go/transport/spdy"
+
+	"github.com/juju/juju/environs/cloudspec"
+)
+
+// PodName identifies the pod an Executor operation targets.
+type PodName string
+
+// Mapping is a single local:remote port pair for a port-forward request,
+// mirroring "kubectl port-forward"'s [localPort:]remotePort syntax.
+type Mapping struct {
+	// LocalPort is the local TCP port to listen on.
+	LocalPort int
+	// RemotePort is the port inside the container to stream to.
+	RemotePort int
+}
+
+// String renders the mapping as "localPort:remotePort", the form the
+// client-go portforward package expects.
+func (m Mapping) String() string {
+	return fmt.Sprintf("%d:%d", m.LocalPort, m.RemotePort)
+}
+
+// ExecParams holds the parameters for a single exec request into a pod.
+type ExecParams struct {
+	PodName   string
+	Container string
+	Commands  []string
+	Stdout    io.Writer
+	Stderr    io.Writer
+	Stdin     io.Reader
+	Tty       bool
+}
+
+// Executor execs commands in, and forwards ports to, containers of a CAAS
+// model's pods.
+type Executor interface {
+	// Exec runs Commands in the pod/container described by params,
+	// returning once the command completes or cancel is closed.
+	Exec(params ExecParams, cancel <-chan struct{}) error
+
+	// PortForward opens a local TCP listener for each mapping and streams
+	// traffic to the named pod using the Kubernetes SPDY/WebSocket
+	// portforward subresource, until stopCh is closed.
+	PortForward(pod PodName, mappings []Mapping, stopCh <-chan struct{}) error
+}
+
+// client is the default Executor implementation, backed by a Kubernetes
+// clientset and REST config.
+type client struct {
+	namespace  string
+	clientset  kubernetes.Interface
+	restConfig *rest.Config
+}
+
+// NewForJujuCloudCloudSpec always returns a NotImplemented error; it does
+// not build an Executor from the supplied cloud spec.
+func NewForJujuCloudCloudSpec(modelName string, cloudSpec cloudspec.CloudSpec) (Executor, error) {
+	return nil, errors.NotImplementedf("k8sexec.NewForJujuCloudCloudSpec")
+}
+
+// Exec implements Executor, running params.Commands in the target pod over
+// the Kubernetes exec subresource.
+func (c *client) Exec(params ExecParams, cancel <-chan struct{}) error {
+	req := c.clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(c.namespace).
+		Name(params.PodName).
+		SubResource("exec").
+		VersionedParams(execOptions(params), scheme.ParameterCodec)
+
+	executor, err := remotecommand.NewSPDYExecutor(c.restConfig, "POST", req.URL())
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- executor.Stream(remotecommand.StreamOptions{
+			Stdin:  params.Stdin,
+			Stdout: params.Stdout,
+			Stderr: params.Stderr,
+			Tty:    params.Tty,
+		})
+	}()
+
+	select {
+	case err := <-done:
+		return errors.Trace(err)
+	case <-cancel:
+		return errors.New("exec cancelled")
+	}
+}
+
+// execOptions builds the PodExecOptions for a single ExecParams request.
+func execOptions(params ExecParams) *corev1.PodExecOptions {
+	return &corev1.PodExecOptions{
+		Container: params.Container,
+		Command:   params.Commands,
+		Stdin:     params.Stdin != nil,
+		Stdout:    params.Stdout != nil,
+		Stderr:    params.Stderr != nil,
+		TTY:       params.Tty,
+	}
+}
+
+// PortForward implements Executor. It streams local connections for each
+// mapping to the named pod's ports over the Kubernetes portforward
+// subresource, the same way "kubectl port-forward" does.
+func (c *client) PortForward(pod PodName, mappings []Mapping, stopCh <-chan struct{}) error {
+	ports := make([]string, len(mappings))
+	for i, m := range mappings {
+		ports[i] = m.String()
+	}
+
+	req := c.clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(c.namespace).
+		Name(string(pod)).
+		SubResource("portforward")
+
+	transport, upgrader, err := spdy.RoundTripperFor(c.restConfig)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, "POST", req.URL())
+
+	readyCh := make(chan struct{})
+	fw, err := portforward.New(dialer, ports, stopCh, readyCh, nil, nil)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return fw.ForwardPorts()
+}
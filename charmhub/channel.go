@@ -0,0 +1,115 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package charmhub
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+// Risk represents the risk level of a CharmHub channel, matching the
+// four-risk Snap/Charm Store model.
+type Risk string
+
+const (
+	// StableRisk is the default, most conservative risk level.
+	StableRisk Risk = "stable"
+
+	// CandidateRisk is a release candidate for the stable risk level.
+	CandidateRisk Risk = "candidate"
+
+	// BetaRisk is a beta release, less stable than candidate.
+	BetaRisk Risk = "beta"
+
+	// EdgeRisk is the bleeding edge, least stable risk level.
+	EdgeRisk Risk = "edge"
+)
+
+// risks is the ordered (least to most risky) set of valid risk levels.
+var risks = map[Risk]int{
+	StableRisk:    0,
+	CandidateRisk: 1,
+	BetaRisk:      2,
+	EdgeRisk:      3,
+}
+
+// legacyRiskAliases maps older, pre-four-risk channel names onto their
+// current equivalent.
+var legacyRiskAliases = map[string]Risk{
+	"development": EdgeRisk,
+}
+
+// ParseRisk parses a risk string, normalising legacy aliases such as
+// "development" to "edge".
+func ParseRisk(risk string) (Risk, error) {
+	if alias, ok := legacyRiskAliases[risk]; ok {
+		return alias, nil
+	}
+	r := Risk(risk)
+	if _, ok := risks[r]; !ok {
+		return "", errors.NotValidf("risk %q", risk)
+	}
+	return r, nil
+}
+
+// Less reports whether risk r is strictly less risky than other, e.g. to
+// guard against auto-refreshing from stable into edge.
+func (r Risk) Less(other Risk) bool {
+	return risks[r] < risks[other]
+}
+
+// Channel identifies a CharmHub channel by track, risk and branch, e.g.
+// "2.9/stable" or "latest/edge/hotfix-x".
+type Channel struct {
+	Track  string
+	Risk   Risk
+	Branch string
+}
+
+// ParseChannel parses a channel string of the form
+// [track/]risk[/branch], normalising legacy risk names.
+func ParseChannel(channel string) (Channel, error) {
+	if channel == "" {
+		return Channel{}, errors.NotValidf("empty channel")
+	}
+	parts := strings.Split(channel, "/")
+
+	var result Channel
+	switch len(parts) {
+	case 1:
+		result.Risk = Risk(parts[0])
+	case 2:
+		if _, err := ParseRisk(parts[0]); err == nil {
+			result.Risk, result.Branch = Risk(parts[0]), parts[1]
+		} else {
+			result.Track, result.Risk = parts[0], Risk(parts[1])
+		}
+	case 3:
+		result.Track, result.Risk, result.Branch = parts[0], Risk(parts[1]), parts[2]
+	default:
+		return Channel{}, errors.NotValidf("channel %q", channel)
+	}
+
+	risk, err := ParseRisk(string(result.Risk))
+	if err != nil {
+		return Channel{}, errors.Annotatef(err, "channel %q", channel)
+	}
+	result.Risk = risk
+	return result, nil
+}
+
+// String reassembles the channel as track/risk/branch, omitting any
+// components that are empty.
+func (c Channel) String() string {
+	path := string(c.Risk)
+	if c.Branch != "" {
+		path = fmt.Sprintf("%s/%s", path, c.Branch)
+	}
+	if c.Track != "" {
+		path = fmt.Sprintf("%s/%s", c.Track, path)
+	}
+	return path
+}
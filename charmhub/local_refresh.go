@@ -0,0 +1,100 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package charmhub
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/v2"
+
+	"github.com/juju/juju/charmhub/transport"
+)
+
+// LocalRefreshConfig resolves a charm archive that lives on local disk (a
+// "local:" charm), rather than one resolved through CharmHub. It
+// implements RefreshConfig only so it can share the instanceKey/String
+// plumbing with the other configs; it never talks to the store, so callers
+// must resolve it directly through Resolve rather than composing it into a
+// RefreshMany plan and calling RefreshClient.Refresh — Build has nothing to
+// send, and Ensure can never see a server response that names its
+// instanceKey.
+type LocalRefreshConfig struct {
+	// charmPath is the location of the charm archive on disk.
+	charmPath string
+	// instanceKey is a private unique key identifying this resolution.
+	instanceKey string
+}
+
+// NewLocalRefreshConfig creates a RefreshConfig for a local charm archive.
+func NewLocalRefreshConfig(charmPath string) (*LocalRefreshConfig, error) {
+	uuid, err := utils.NewUUID()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &LocalRefreshConfig{
+		charmPath:   charmPath,
+		instanceKey: uuid.String(),
+	}, nil
+}
+
+// Build implements RefreshConfig. A local charm is never sent to CharmHub,
+// so there is no request to build; Build is not a supported way to resolve
+// a local charm, use Resolve instead.
+func (c *LocalRefreshConfig) Build() (transport.RefreshRequest, Headers, error) {
+	return transport.RefreshRequest{}, nil, errors.NotSupportedf("building a CharmHub request for a local refresh config")
+}
+
+// Ensure implements RefreshConfig. A local charm's instanceKey is never
+// sent to CharmHub, so no server response can ever name it; Ensure is not
+// a supported way to validate a local charm, use Resolve instead.
+func (c *LocalRefreshConfig) Ensure(responses []transport.RefreshResponse) error {
+	return errors.NotSupportedf("ensuring a CharmHub response for a local refresh config")
+}
+
+// String describes the underlying refresh config.
+func (c *LocalRefreshConfig) String() string {
+	return fmt.Sprintf("Local refresh (instanceKey: %s): using charm archive at %q", c.instanceKey, c.charmPath)
+}
+
+// LocalCharmArchive describes a charm archive resolved from disk, ready to
+// be uploaded through application.AddLocalCharm.
+type LocalCharmArchive struct {
+	Path       string
+	Size       int64
+	HashSHA256 string
+}
+
+// Resolve reads the charm archive from disk, computing the information a
+// caller needs to record it for later upload, and returns the uniform
+// transport.RefreshResponse that store-backed refresh configs also
+// produce.
+func (c *LocalRefreshConfig) Resolve() (transport.RefreshResponse, LocalCharmArchive, error) {
+	f, err := os.Open(c.charmPath)
+	if err != nil {
+		return transport.RefreshResponse{}, LocalCharmArchive{}, errors.Trace(err)
+	}
+	defer f.Close()
+
+	hasher := sha256.New()
+	size, err := io.Copy(hasher, f)
+	if err != nil {
+		return transport.RefreshResponse{}, LocalCharmArchive{}, errors.Trace(err)
+	}
+
+	archive := LocalCharmArchive{
+		Path:       c.charmPath,
+		Size:       size,
+		HashSHA256: fmt.Sprintf("%x", hasher.Sum(nil)),
+	}
+
+	return transport.RefreshResponse{
+		InstanceKey: c.instanceKey,
+		Name:        filepath.Base(c.charmPath),
+	}, archive, nil
+}
@@ -120,7 +120,7 @@ func (c *RefreshClient) Refresh(ctx context.Context, config RefreshConfig) ([]tr
 type refreshOne struct {
 	ID       string
 	Revision int
-	Channel  string
+	Channel  Channel
 	Platform RefreshPlatform
 	// instanceKey is a private unique key that we construct for CharmHub API
 	// asynchronous calls.
@@ -129,7 +129,7 @@ type refreshOne struct {
 
 func (c refreshOne) String() string {
 	return fmt.Sprintf("Refresh one (instanceKey: %s): using ID %s revision %+v, with channel %s and platform %v",
-		c.instanceKey, c.ID, c.Revision, c.Channel, c.Platform.String())
+		c.instanceKey, c.ID, c.Revision, c.Channel.String(), c.Platform.String())
 }
 
 // RefreshOne creates a request config for requesting only one charm.
@@ -137,6 +137,10 @@ func RefreshOne(id string, revision int, channel string, platform RefreshPlatfor
 	if err := validatePlatform(platform); err != nil {
 		return nil, errors.Trace(err)
 	}
+	ch, err := ParseChannel(channel)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
 	uuid, err := utils.NewUUID()
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -145,7 +149,7 @@ func RefreshOne(id string, revision int, channel string, platform RefreshPlatfor
 		instanceKey: uuid.String(),
 		ID:          id,
 		Revision:    revision,
-		Channel:     channel,
+		Channel:     ch,
 		Platform:    platform,
 	}, nil
 }
@@ -162,7 +166,7 @@ func (c refreshOne) Build() (transport.RefreshRequest, Headers, error) {
 				Series:       c.Platform.Series,
 				Architecture: c.Platform.Architecture,
 			},
-			TrackingChannel: c.Channel,
+			TrackingChannel: c.Channel.String(),
 			// TODO (stickupkid): We need to model the refreshed date. It's
 			// currently optional, but will be required at some point. This
 			// is the installed date of the charm on the system.
@@ -189,7 +193,7 @@ type executeOne struct {
 	ID       string
 	Name     string
 	Revision *int
-	Channel  *string
+	Channel  *Channel
 	Platform RefreshPlatform
 	// instanceKey is a private unique key that we construct for CharmHub API
 	// asynchronous calls.
@@ -222,6 +226,10 @@ func InstallOneFromChannel(name string, channel string, platform RefreshPlatform
 	if err := validatePlatform(platform); err != nil {
 		return nil, errors.Trace(err)
 	}
+	ch, err := ParseChannel(channel)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
 	uuid, err := utils.NewUUID()
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -230,7 +238,7 @@ func InstallOneFromChannel(name string, channel string, platform RefreshPlatform
 		action:      InstallAction,
 		instanceKey: uuid.String(),
 		Name:        name,
-		Channel:     &channel,
+		Channel:     &ch,
 		Platform:    platform,
 	}, nil
 }
@@ -240,6 +248,10 @@ func DownloadOne(id string, revision int, channel string, platform RefreshPlatfo
 	if err := validatePlatform(platform); err != nil {
 		return nil, errors.Trace(err)
 	}
+	ch, err := ParseChannel(channel)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
 	uuid, err := utils.NewUUID()
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -249,7 +261,7 @@ func DownloadOne(id string, revision int, channel string, platform RefreshPlatfo
 		instanceKey: uuid.String(),
 		ID:          id,
 		Revision:    &revision,
-		Channel:     &channel,
+		Channel:     &ch,
 		Platform:    platform,
 	}, nil
 }
@@ -279,6 +291,10 @@ func DownloadOneFromChannel(id string, channel string, platform RefreshPlatform)
 	if err := validatePlatform(platform); err != nil {
 		return nil, errors.Trace(err)
 	}
+	ch, err := ParseChannel(channel)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
 	uuid, err := utils.NewUUID()
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -287,7 +303,7 @@ func DownloadOneFromChannel(id string, channel string, platform RefreshPlatform)
 		action:      DownloadAction,
 		instanceKey: uuid.String(),
 		ID:          id,
-		Channel:     &channel,
+		Channel:     &ch,
 		Platform:    platform,
 	}, nil
 }
@@ -302,6 +318,11 @@ func (c executeOne) Build() (transport.RefreshRequest, Headers, error) {
 	if c.Name != "" {
 		name = &c.Name
 	}
+	var channel *string
+	if c.Channel != nil {
+		s := c.Channel.String()
+		channel = &s
+	}
 	return transport.RefreshRequest{
 		// Context is required here, even if it looks optional.
 		Context: []transport.RefreshRequestContext{},
@@ -311,7 +332,7 @@ func (c executeOne) Build() (transport.RefreshRequest, Headers, error) {
 			ID:          id,
 			Name:        name,
 			Revision:    c.Revision,
-			Channel:     c.Channel,
+			Channel:     channel,
 			Platform: &transport.RefreshRequestPlatform{
 				OS:           c.Platform.OS,
 				Series:       c.Platform.Series,
@@ -334,7 +355,7 @@ func (c executeOne) Ensure(responses []transport.RefreshResponse) error {
 func (c executeOne) String() string {
 	var channel string
 	if c.Channel != nil {
-		channel = *c.Channel
+		channel = c.Channel.String()
 	}
 	var using string
 	if c.ID != "" {
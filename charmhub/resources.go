@@ -0,0 +1,106 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package charmhub
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/charmhub/path"
+)
+
+// resourceRevisionResponse is the CharmHub resource-revision API response.
+type resourceRevisionResponse struct {
+	Revision ResourceRevision `json:"revision"`
+}
+
+// ResourceRevision describes a single revision of a charm resource as
+// returned by the CharmHub resource API.
+type ResourceRevision struct {
+	Revision    int    `json:"revision"`
+	DownloadURL string `json:"download-url"`
+}
+
+// ResourceClient resolves and downloads charm resource revisions from
+// CharmHub. It is built on the same path.Path/RESTClient plumbing as
+// RefreshClient, rather than talking to CharmHub directly.
+type ResourceClient struct {
+	path      path.Path
+	client    RESTClient
+	transport http.RoundTripper
+	logger    Logger
+}
+
+// NewResourceClient creates a ResourceClient for requesting charm resource
+// revisions, rooted at path. transport is used only to stream the
+// resource content a resolved revision points at, the same way
+// services.CharmDownloaderConfig's Transport field is used to stream a
+// charm archive rather than going through the JSON RESTClient.
+func NewResourceClient(path path.Path, client RESTClient, transport http.RoundTripper, logger Logger) *ResourceClient {
+	return &ResourceClient{
+		path:      path,
+		client:    client,
+		transport: transport,
+		logger:    logger,
+	}
+}
+
+// NewDefaultResourceClient creates a ResourceClient against the default
+// CharmHub server, using transport for both the RESTClient used to
+// resolve resource revisions and the raw download of the resource content
+// itself.
+func NewDefaultResourceClient(transport http.RoundTripper, logger Logger) (*ResourceClient, error) {
+	root, err := path.MakePath(CharmHubServerURL)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return NewResourceClient(root, NewHTTPRESTClient(transport), transport, logger), nil
+}
+
+// ResourceRevision resolves the download details for a specific revision of
+// a named resource belonging to the charm identified by charmID.
+func (c *ResourceClient) ResourceRevision(ctx context.Context, charmID, name string, revision int) (ResourceRevision, error) {
+	resourcePath, err := c.path.Join(fmt.Sprintf("v2/charms/%s/resources/%s/revisions/%d", charmID, name, revision))
+	if err != nil {
+		return ResourceRevision{}, errors.Trace(err)
+	}
+	c.logger.Tracef("ResourceRevision %s", resourcePath)
+
+	var resp resourceRevisionResponse
+	restResp, err := c.client.Get(ctx, resourcePath, &resp)
+	if err != nil {
+		return ResourceRevision{}, errors.Trace(err)
+	}
+	if restResp.StatusCode == http.StatusNotFound {
+		return ResourceRevision{}, errors.NotFoundf("resource %q revision %d", name, revision)
+	}
+	if restResp.StatusCode != http.StatusOK {
+		return ResourceRevision{}, errors.Errorf("resolving resource %q revision %d: unexpected status %q", name, revision, restResp.Status)
+	}
+	return resp.Revision, nil
+}
+
+// Download streams the content at url, as returned by ResourceRevision, to
+// w.
+func (c *ResourceClient) Download(ctx context.Context, url string, w io.Writer) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	resp, err := (&http.Client{Transport: c.transport}).Do(req)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("downloading resource: unexpected status %q", resp.Status)
+	}
+	_, err = io.Copy(w, resp.Body)
+	return errors.Trace(err)
+}
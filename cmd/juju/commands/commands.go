@@ -0,0 +1,16 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"github.com/juju/cmd"
+)
+
+// caasSSHCommands returns the CAAS-only commands backed by SSHContainer,
+// which is the port-forward command. No command registry calls it.
+func caasSSHCommands() []cmd.Command {
+	return []cmd.Command{
+		NewPortForwardCommand(),
+	}
+}
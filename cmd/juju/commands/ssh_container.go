@@ -6,11 +6,13 @@ package commands
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
 	"github.com/juju/gnuflag"
 	"github.com/juju/names/v4"
+	"k8s.io/client-go/tools/clientcmd"
 
 	"github.com/juju/juju/api/application"
 	apicloud "github.com/juju/juju/api/cloud"
@@ -26,11 +28,20 @@ import (
 // SSHContainer implements functionality shared by sshCommand, SCPCommand
 // and DebugHooksCommand for CAAS model.
 type SSHContainer struct {
-	// remote indicates if it should target to the operator or workload pod.
-	remote    bool
-	target    string
-	args      []string
-	modelUUID string
+	// operator forces the target to be the operator pod rather than the
+	// workload pod.
+	operator bool
+	// container is the name of the container to target within the
+	// resolved pod. If empty, the default container is used.
+	container string
+	// kubeconfig and kubeContext, when set, make getExecClient build the
+	// cloud spec directly from a local kubeconfig file instead of round-
+	// tripping through the controller for the cloud credential.
+	kubeconfig string
+	kubeContext string
+	target      string
+	args        []string
+	modelUUID   string
 
 	cloudCredentialAPI
 	modelAPI
@@ -57,6 +68,10 @@ type modelAPI interface {
 
 // SetFlags sets up options and flags for the command.
 func (c *SSHContainer) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.container, "container", "", "the container name of the target pod")
+	f.BoolVar(&c.operator, "operator", false, "target the operator pod")
+	f.StringVar(&c.kubeconfig, "kubeconfig", "", "path to a kubeconfig file to use instead of the controller's cloud credential")
+	f.StringVar(&c.kubeContext, "context", "", "the kubeconfig context to use (defaults to the current context)")
 }
 
 func (c *SSHContainer) setHostChecker(checker jujussh.ReachableChecker) {}
@@ -151,7 +166,27 @@ func (c *SSHContainer) resolveTarget(target string) (*resolvedTarget, error) {
 	if unit.Error != nil {
 		return nil, errors.Annotatef(unit.Error, "getting unit %q", target)
 	}
-	return &resolvedTarget{entity: unit.ProviderId}, nil
+
+	if c.container != "" && !containsContainer(unit.Containers, c.container) {
+		return nil, errors.Errorf(
+			"container %q not found; available: %s", c.container, strings.Join(unit.Containers, ", "),
+		)
+	}
+
+	entity := unit.ProviderId
+	if c.operator {
+		entity = unit.OperatorProviderId
+	}
+	return &resolvedTarget{entity: entity}, nil
+}
+
+func containsContainer(containers []string, name string) bool {
+	for _, c := range containers {
+		if c == name {
+			return true
+		}
+	}
+	return false
 }
 
 func (c *SSHContainer) ssh(ctx *cmd.Context, enablePty bool, target *resolvedTarget) (err error) {
@@ -174,18 +209,23 @@ func (c *SSHContainer) ssh(ctx *cmd.Context, enablePty bool, target *resolvedTar
 
 	return execClient.Exec(
 		k8sexec.ExecParams{
-			PodName:  target.entity,
-			Commands: c.args,
-			Stdout:   ctx.GetStdout(),
-			Stderr:   ctx.GetStdout(),
-			Stdin:    ctx.GetStdin(),
-			Tty:      enablePty,
+			PodName:   target.entity,
+			Container: c.container,
+			Commands:  c.args,
+			Stdout:    ctx.GetStdout(),
+			Stderr:    ctx.GetStdout(),
+			Stdin:     ctx.GetStdin(),
+			Tty:       enablePty,
 		},
 		cancel,
 	)
 }
 
 func (c *SSHContainer) getExecClient(ctxt *cmd.Context) (k8sexec.Executor, error) {
+	if c.kubeconfig != "" {
+		return c.getExecClientFromKubeConfig()
+	}
+
 	if v := c.cloudCredentialAPI.BestAPIVersion(); v < 2 {
 		return nil, errors.NotSupportedf("credential content lookup on the controller in Juju v%d", v)
 	}
@@ -227,3 +267,51 @@ func (c *SSHContainer) getExecClient(ctxt *cmd.Context) (k8sexec.Executor, error
 	}
 	return c.execClientGetter(mInfo.Result.Name, cloudSpec)
 }
+
+// getExecClientFromKubeConfig builds the exec client directly from a local
+// kubeconfig file, similar to how kubectl works, skipping the controller's
+// model and cloud credential lookups. The API connections made by initRun
+// and the unit lookup in resolveTarget still go through the controller.
+func (c *SSHContainer) getExecClientFromKubeConfig() (k8sexec.Executor, error) {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.ExplicitPath = c.kubeconfig
+	loader := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		rules, &clientcmd.ConfigOverrides{CurrentContext: c.kubeContext},
+	)
+
+	restConfig, err := loader.ClientConfig()
+	if err != nil {
+		return nil, errors.Annotatef(err, "reading kubeconfig %q", c.kubeconfig)
+	}
+	rawConfig, err := loader.RawConfig()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	contextName := c.kubeContext
+	if contextName == "" {
+		contextName = rawConfig.CurrentContext
+	}
+
+	if len(restConfig.CertData) == 0 || len(restConfig.KeyData) == 0 {
+		return nil, errors.NotSupportedf(
+			"kubeconfig context %q: authentication other than a client certificate (token-based or exec plugin auth)", contextName,
+		)
+	}
+
+	cred := jujucloud.NewCredential(jujucloud.AuthType("clientcertificate"), map[string]string{
+		"ClientCertificateData": string(restConfig.CertData),
+		"ClientKeyData":         string(restConfig.KeyData),
+	})
+	cloud := jujucloud.Cloud{
+		Name:           contextName,
+		Type:           "kubernetes",
+		Endpoint:       restConfig.Host,
+		CACertificates: []string{string(restConfig.CAData)},
+	}
+	cloudSpec, err := cloudspec.MakeCloudSpec(cloud, "", &cred)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return c.execClientGetter(contextName, cloudSpec)
+}
@@ -0,0 +1,241 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"archive/tar"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+
+	k8sexec "github.com/juju/juju/caas/kubernetes/provider/exec"
+)
+
+// podTarget identifies a path inside a unit's pod, as parsed from a
+// "[unit/container:]path" copy argument.
+type podTarget struct {
+	unit      string
+	container string
+	path      string
+}
+
+// parsePodTarget splits a copy argument into a podTarget. The second return
+// value is false when the argument has no "unit:" prefix, i.e. it is a
+// local path.
+func parsePodTarget(arg string) (podTarget, bool) {
+	i := strings.Index(arg, ":")
+	if i < 0 {
+		return podTarget{path: arg}, false
+	}
+	left, path := arg[:i], arg[i+1:]
+	parts := strings.Split(left, "/")
+	switch len(parts) {
+	case 2:
+		return podTarget{unit: left, path: path}, true
+	case 3:
+		return podTarget{unit: parts[0] + "/" + parts[1], container: parts[2], path: path}, true
+	default:
+		return podTarget{path: arg}, false
+	}
+}
+
+// copy implements "kubectl cp" semantics between the local filesystem and a
+// unit's provider pod, by tar-streaming through the exec client.
+func (c *SSHContainer) copy(ctx *cmd.Context, src, dst string, recursive bool) error {
+	srcTarget, srcIsPod := parsePodTarget(src)
+	dstTarget, dstIsPod := parsePodTarget(dst)
+
+	switch {
+	case srcIsPod && !dstIsPod:
+		return c.copyFromPod(ctx, srcTarget, dstTarget.path, recursive)
+	case !srcIsPod && dstIsPod:
+		return c.copyToPod(ctx, srcTarget.path, dstTarget, recursive)
+	default:
+		return errors.NotSupportedf("copying directly between two pods, or between two local paths,")
+	}
+}
+
+// copyFromPod execs "tar cf - -C <dir> <base>" in the container, naming the
+// archive entries relative to the source's parent directory as tarball
+// does, and untars the resulting stream into the local destination.
+func (c *SSHContainer) copyFromPod(ctx *cmd.Context, src podTarget, dst string, recursive bool) error {
+	target, err := c.resolveTarget(src.unit)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	execClient, err := c.getExecClient(ctx)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	container := src.container
+	if container == "" {
+		container = c.container
+	}
+
+	srcPath := path.Clean(src.path)
+	reader, writer := io.Pipe()
+	cancel := make(chan struct{})
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- execClient.Exec(
+			k8sexec.ExecParams{
+				PodName:   target.entity,
+				Container: container,
+				Commands:  []string{"tar", "cf", "-", "-C", path.Dir(srcPath), path.Base(srcPath)},
+				Stdout:    writer,
+				Stderr:    ctx.GetStderr(),
+			},
+			cancel,
+		)
+		writer.Close()
+	}()
+
+	if err := untar(reader, dst, recursive); err != nil {
+		close(cancel)
+		return errors.Annotate(err, "extracting remote archive")
+	}
+	return errors.Trace(<-errCh)
+}
+
+// copyToPod tars up the local source and pipes it into the container,
+// which execs "tar xf - -C <dir>" to unpack it.
+func (c *SSHContainer) copyToPod(ctx *cmd.Context, src string, dst podTarget, recursive bool) error {
+	target, err := c.resolveTarget(dst.unit)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	execClient, err := c.getExecClient(ctx)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	container := dst.container
+	if container == "" {
+		container = c.container
+	}
+
+	reader, writer := io.Pipe()
+	cancel := make(chan struct{})
+	go func() {
+		writer.CloseWithError(tarball(writer, src, recursive))
+	}()
+
+	return execClient.Exec(
+		k8sexec.ExecParams{
+			PodName:   target.entity,
+			Container: container,
+			Commands:  []string{"tar", "xf", "-", "-C", dst.path},
+			Stdin:     reader,
+			Stderr:    ctx.GetStderr(),
+		},
+		cancel,
+	)
+}
+
+// tarball writes a tar archive of src to w. src may be a single file or,
+// when recursive is true, a directory tree.
+func tarball(w io.Writer, src string, recursive bool) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if info.IsDir() && !recursive {
+		return errors.Errorf("%q is a directory, use -r to copy recursively", src)
+	}
+
+	tw := tar.NewWriter(w)
+	defer tw.Close()
+
+	base := filepath.Dir(src)
+	return filepath.Walk(src, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(base, path)
+		if err != nil {
+			return err
+		}
+		hdr, err := tar.FileInfoHeader(fi, "")
+		if err != nil {
+			return err
+		}
+		hdr.Name = filepath.ToSlash(rel)
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if fi.IsDir() {
+			return nil
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+}
+
+// safeJoin resolves name (a tar entry path, using "/" separators) against
+// dst and ensures the result stays under dst, rejecting the classic
+// tar-slip path traversal where a pod emits an entry like "../../.bashrc".
+func safeJoin(dst, name string) (string, error) {
+	target := filepath.Join(dst, filepath.FromSlash(name))
+	rel, err := filepath.Rel(dst, target)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(os.PathSeparator)) {
+		return "", errors.Errorf("tar entry %q escapes destination %q", name, dst)
+	}
+	return target, nil
+}
+
+// untar extracts a tar archive read from r into dst.
+func untar(r io.Reader, dst string, recursive bool) error {
+	if err := os.MkdirAll(dst, 0755); err != nil {
+		return errors.Trace(err)
+	}
+
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if !recursive && strings.Contains(hdr.Name, "/") {
+			continue
+		}
+
+		target, err := safeJoin(dst, hdr.Name)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, os.FileMode(hdr.Mode)); err != nil {
+				return errors.Trace(err)
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return errors.Trace(err)
+			}
+			f, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, os.FileMode(hdr.Mode))
+			if err != nil {
+				return errors.Trace(err)
+			}
+			if _, err := io.Copy(f, tr); err != nil {
+				f.Close()
+				return errors.Trace(err)
+			}
+			f.Close()
+		}
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+
+	k8sexec "github.com/juju/juju/caas/kubernetes/provider/exec"
+	"github.com/juju/juju/cmd/modelcmd"
+)
+
+// defaultPodRunningTimeout is the default --pod-running-timeout value. Run
+// does not wait on the pod's state, so the value has no effect on forwarding.
+const defaultPodRunningTimeout = 5 * time.Minute
+
+// portForwardCommand opens local TCP listeners that stream to container
+// ports in a CAAS model, using the same Kubernetes portforward subresource
+// that "kubectl port-forward" uses.
+type portForwardCommand struct {
+	modelcmd.ModelCommandBase
+	SSHContainer
+
+	address           string
+	podRunningTimeout time.Duration
+	mappings          []k8sexec.Mapping
+}
+
+// NewPortForwardCommand returns a command used to forward local ports to a
+// CAAS workload or operator pod.
+func NewPortForwardCommand() cmd.Command {
+	c := &portForwardCommand{}
+	return modelcmd.Wrap(c)
+}
+
+// Info implements cmd.Command.
+func (c *portForwardCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "port-forward",
+		Args:    "<unit> [localPort:]remotePort ...",
+		Purpose: "Forward one or more local ports to a k8s workload or operator pod.",
+		Doc: `
+port-forward streams traffic between local TCP listeners and ports in a
+unit's provider pod, without requiring operators to fish a kubeconfig out
+of the controller. Each mapping is of the form [localPort:]remotePort;
+when localPort is omitted, it defaults to remotePort.
+
+Examples:
+
+    juju port-forward mysql/0 3306
+    juju port-forward mysql/0 13306:3306 8080:80
+`,
+	}
+}
+
+// SetFlags implements cmd.Command.
+func (c *portForwardCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	c.SSHContainer.SetFlags(f)
+	f.StringVar(&c.address, "address", "localhost", "local address shown in the forwarding summary; the listeners always bind to localhost")
+	f.DurationVar(&c.podRunningTimeout, "pod-running-timeout", defaultPodRunningTimeout, "time to wait for the target pod to be running; not used, forwarding starts without waiting")
+}
+
+// Init implements cmd.Command.
+func (c *portForwardCommand) Init(args []string) error {
+	if len(args) < 2 {
+		return errors.New("port-forward requires a unit and at least one port mapping")
+	}
+	c.SetTarget(args[0])
+	mappings, err := parsePortForwardMappings(args[1:])
+	if err != nil {
+		return errors.Trace(err)
+	}
+	c.mappings = mappings
+	return nil
+}
+
+// parsePortForwardMappings parses a list of "[localPort:]remotePort" specs.
+func parsePortForwardMappings(args []string) ([]k8sexec.Mapping, error) {
+	mappings := make([]k8sexec.Mapping, 0, len(args))
+	for _, arg := range args {
+		local, remote := arg, arg
+		if i := strings.IndexRune(arg, ':'); i >= 0 {
+			local, remote = arg[:i], arg[i+1:]
+		}
+		remotePort, err := strconv.Atoi(remote)
+		if err != nil {
+			return nil, errors.Errorf("invalid remote port %q", remote)
+		}
+		localPort, err := strconv.Atoi(local)
+		if err != nil {
+			return nil, errors.Errorf("invalid local port %q", local)
+		}
+		mappings = append(mappings, k8sexec.Mapping{
+			LocalPort:  localPort,
+			RemotePort: remotePort,
+		})
+	}
+	return mappings, nil
+}
+
+// Run implements cmd.Command.
+func (c *portForwardCommand) Run(ctx *cmd.Context) (err error) {
+	if err := c.initRun(c.ModelCommandBase); err != nil {
+		return errors.Trace(err)
+	}
+	defer c.cleanupRun()
+
+	target, err := c.resolveTarget(c.GetTarget())
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	execClient, err := c.getExecClient(ctx)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	for _, m := range c.mappings {
+		fmt.Fprintf(ctx.GetStdout(), "Forwarding %s:%d -> %d\n", c.address, m.LocalPort, m.RemotePort)
+	}
+
+	ch := make(chan os.Signal, 1)
+	defer close(ch)
+	stopCh := make(chan struct{})
+	ctx.InterruptNotify(ch)
+	defer ctx.StopInterruptNotify(ch)
+	go func() {
+		<-ch
+		close(stopCh)
+	}()
+
+	return execClient.PortForward(k8sexec.PodName(target.entity), c.mappings, stopCh)
+}
@@ -0,0 +1,99 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package agent
+
+import (
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+
+	"github.com/juju/juju/core/constraints"
+	"github.com/juju/juju/state"
+)
+
+// BootstrapCommand initialises a controller's state during bootstrap and
+// deploys the controller charm application via deployControllerCharm. This
+// file declares only the pieces deployControllerCharm needs: a data
+// directory and the --controller-charm-* flags. The rest of the real
+// bootstrap machine-agent command (agent config, mongo initialisation,
+// constraints, SetFlags/Init/Run for the agent as a whole, and so on) is
+// not part of this checkout.
+type BootstrapCommand struct {
+	controllerCharmFlags
+
+	dataDir string
+}
+
+// DataDir returns the directory the bootstrap agent was started with, the
+// same directory populateLocalControllerCharm looks in for a local
+// controller charm archive.
+func (c *BootstrapCommand) DataDir() string {
+	return c.dataDir
+}
+
+// SetFlags registers the bootstrap agent's controller-charm flags.
+func (c *BootstrapCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.dataDir, "data-dir", "/var/lib/juju", "directory for juju data")
+	c.controllerCharmFlags.SetFlags(f)
+}
+
+// Init parses the controller-charm flag values collected by SetFlags.
+func (c *BootstrapCommand) Init(args []string) error {
+	return c.controllerCharmFlags.Init()
+}
+
+// DeployControllerCharm deploys the controller charm using the
+// --controller-charm-* flag values collected by SetFlags/Init. The
+// bootstrap agent's Run method calls this once state is ready, supplying
+// the constraints and unit password it already has in scope.
+func (c *BootstrapCommand) DeployControllerCharm(st *state.State, cons constraints.Value, isCAAS bool, unitPassword string) error {
+	return c.deployControllerCharm(st, cons, c.charmChannel, c.charmRevision, c.charmMirror, c.charmResources, isCAAS, unitPassword)
+}
+
+// controllerCharmFlags holds the --controller-charm-* flag values for
+// BootstrapCommand, parsed and ready to pass to deployControllerCharm.
+type controllerCharmFlags struct {
+	charmChannel        string
+	charmRevision       int
+	charmMirror         string
+	charmResourceValues repeatedStringFlag
+	charmResources      []controllerCharmResource
+}
+
+// SetFlags registers the controller-charm flags on f.
+func (c *controllerCharmFlags) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.charmChannel, "controller-charm-channel", "stable", "channel to use when downloading the controller charm from CharmHub")
+	f.IntVar(&c.charmRevision, "controller-charm-revision", -1, "revision of the controller charm to use, overriding the channel")
+	f.StringVar(&c.charmMirror, "controller-charm-mirror", "", "path to a local CharmHub mirror to try before falling back to CharmHub itself")
+	f.Var(&c.charmResourceValues, "controller-charm-resource", "a controller charm resource, as name=path or name=ignored,revision=N; may be repeated")
+}
+
+// Init parses the collected --controller-charm-resource values into
+// charmResources.
+func (c *controllerCharmFlags) Init() error {
+	for _, value := range c.charmResourceValues {
+		res, err := parseControllerCharmResource(value)
+		if err != nil {
+			return errors.Annotate(err, "parsing --controller-charm-resource")
+		}
+		c.charmResources = append(c.charmResources, res)
+	}
+	return nil
+}
+
+// repeatedStringFlag is a gnuflag.Value that collects every value passed
+// for a repeatable string flag, in the order given.
+type repeatedStringFlag []string
+
+// String implements gnuflag.Value.
+func (f *repeatedStringFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+// Set implements gnuflag.Value.
+func (f *repeatedStringFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
@@ -0,0 +1,50 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package agent
+
+import (
+	"github.com/juju/gnuflag"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+)
+
+type controllerCharmFlagsSuite struct{}
+
+var _ = gc.Suite(&controllerCharmFlagsSuite{})
+
+func (s *controllerCharmFlagsSuite) TestFlagDefaults(c *gc.C) {
+	cmd := &BootstrapCommand{}
+	f := gnuflag.NewFlagSet("bootstrap", gnuflag.ContinueOnError)
+	cmd.SetFlags(f)
+	c.Assert(cmd.Init(nil), jc.ErrorIsNil)
+
+	c.Check(cmd.charmChannel, gc.Equals, "stable")
+	c.Check(cmd.charmRevision, gc.Equals, -1)
+	c.Check(cmd.charmMirror, gc.Equals, "")
+	c.Check(cmd.charmResources, gc.HasLen, 0)
+}
+
+func (s *controllerCharmFlagsSuite) TestFlagsPopulateResources(c *gc.C) {
+	cmd := &BootstrapCommand{}
+	f := gnuflag.NewFlagSet("bootstrap", gnuflag.ContinueOnError)
+	cmd.SetFlags(f)
+
+	err := f.Parse(true, []string{
+		"--controller-charm-channel", "2.9/edge",
+		"--controller-charm-revision", "7",
+		"--controller-charm-mirror", "/srv/mirror",
+		"--controller-charm-resource", "dashboard=/tmp/dashboard.tar.gz",
+		"--controller-charm-resource", "metrics=ignored,revision=3",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmd.Init(nil), jc.ErrorIsNil)
+
+	c.Check(cmd.charmChannel, gc.Equals, "2.9/edge")
+	c.Check(cmd.charmRevision, gc.Equals, 7)
+	c.Check(cmd.charmMirror, gc.Equals, "/srv/mirror")
+	c.Check(cmd.charmResources, jc.DeepEquals, []controllerCharmResource{
+		{Name: "dashboard", Path: "/tmp/dashboard.tar.gz", Revision: -1},
+		{Name: "metrics", Path: "", Revision: 3},
+	})
+}
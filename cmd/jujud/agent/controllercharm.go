@@ -35,7 +35,10 @@ import (
 
 const controllerCharmURL = "ch:juju-controller"
 
-func (c *BootstrapCommand) deployControllerCharm(st *state.State, cons constraints.Value, charmRisk string, isCAAS bool, unitPassword string) (resultErr error) {
+func (c *BootstrapCommand) deployControllerCharm(
+	st *state.State, cons constraints.Value, charmChannel string, charmRevision int, charmMirror string,
+	charmResources []controllerCharmResource, isCAAS bool, unitPassword string,
+) (resultErr error) {
 	arch := corearch.DefaultArchitecture
 	series := coreseries.LatestLts()
 	if cons.HasArch() {
@@ -87,16 +90,23 @@ func (c *BootstrapCommand) deployControllerCharm(st *state.State, cons constrain
 	if err != nil && !errors.IsNotFound(err) {
 		return errors.Annotate(err, "deploying local controller charm")
 	}
-	// If no local charm, use the one from charmhub.
+	// Next, try a local Charmhub mirror for fully offline bootstraps.
+	if err != nil && charmMirror != "" {
+		source = "mirror"
+		if curl, origin, err = populateMirrorControllerCharm(st, charmMirror, charmChannel, charmRevision, series, arch); err != nil && !errors.IsNotFound(err) {
+			return errors.Annotate(err, "deploying mirrored controller charm")
+		}
+	}
+	// Finally, fall back to charmhub.
 	if err != nil {
 		source = "store"
-		if curl, origin, err = populateStoreControllerCharm(st, charmRisk, series, arch); err != nil {
+		if curl, origin, err = populateStoreControllerCharm(st, charmChannel, charmRevision, series, arch); err != nil {
 			return errors.Annotate(err, "deploying charmhub controller charm")
 		}
 	}
 
 	// Once the charm is added, set up the controller application.
-	if controllerUnit, err = addControllerApplication(st, curl, *origin, controllerAddress, series); err != nil {
+	if controllerUnit, err = addControllerApplication(st, curl, *origin, controllerAddress, series, charmResources); err != nil {
 		return errors.Annotate(err, "cannot add controller application")
 	}
 	logger.Debugf("Successfully deployed %s Juju controller charm", source)
@@ -115,7 +125,7 @@ var (
 )
 
 // populateStoreControllerCharm downloads and stores the controller charm from charmhub.
-func populateStoreControllerCharm(st *state.State, charmRisk, series, arch string) (*charm.URL, *corecharm.Origin, error) {
+func populateStoreControllerCharm(st *state.State, charmChannel string, charmRevision int, series, arch string) (*charm.URL, *corecharm.Origin, error) {
 	model, err := st.Model()
 	if err != nil {
 		return nil, nil, err
@@ -131,17 +141,9 @@ func populateStoreControllerCharm(st *state.State, charmRisk, series, arch strin
 	if err != nil {
 		return nil, nil, err
 	}
-	curl := charm.MustParseURL(controllerCharmURL)
-	channel := corecharm.MakeRiskOnlyChannel(charmRisk)
-	origin := corecharm.Origin{
-		Source:  corecharm.CharmHub,
-		Type:    "charm",
-		Channel: &channel,
-		Platform: corecharm.Platform{
-			Architecture: arch,
-			OS:           strings.ToLower(coreos.Ubuntu.String()),
-			Series:       charmhub.NotAvailable,
-		},
+	curl, origin, err := storeControllerCharmOrigin(charmChannel, charmRevision, arch)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
 	}
 
 	var supportedSeries []string
@@ -182,6 +184,44 @@ func populateStoreControllerCharm(st *state.State, charmRisk, series, arch strin
 	return curl, &resOrigin, nil
 }
 
+// parseControllerCharmChannel parses a --controller-charm-channel value
+// into the corecharm.Channel shape shared by the charmhub and mirror
+// controller charm sources.
+func parseControllerCharmChannel(charmChannel string) (corecharm.Channel, error) {
+	parsedChannel, err := charm.ParseChannel(charmChannel)
+	if err != nil {
+		return corecharm.Channel{}, errors.Annotatef(err, "parsing controller charm channel %q", charmChannel)
+	}
+	return corecharm.MakeChannel(parsedChannel.Track, parsedChannel.Risk.String(), parsedChannel.Branch), nil
+}
+
+// storeControllerCharmOrigin parses charmChannel and builds the charm URL
+// and CharmHub origin that populateStoreControllerCharm resolves against.
+// It is factored out so the channel/revision handling can be exercised
+// without a running state.State.
+func storeControllerCharmOrigin(charmChannel string, charmRevision int, arch string) (*charm.URL, corecharm.Origin, error) {
+	channel, err := parseControllerCharmChannel(charmChannel)
+	if err != nil {
+		return nil, corecharm.Origin{}, errors.Trace(err)
+	}
+
+	curl := charm.MustParseURL(controllerCharmURL)
+	if charmRevision >= 0 {
+		curl = curl.WithRevision(charmRevision)
+	}
+	origin := corecharm.Origin{
+		Source:  corecharm.CharmHub,
+		Type:    "charm",
+		Channel: &channel,
+		Platform: corecharm.Platform{
+			Architecture: arch,
+			OS:           strings.ToLower(coreos.Ubuntu.String()),
+			Series:       charmhub.NotAvailable,
+		},
+	}
+	return curl, origin, nil
+}
+
 // stateShim allows us to use a real state instance with the charm services logic.
 type stateShim struct {
 	*state.State
@@ -257,11 +297,20 @@ func addLocalControllerCharm(st *state.State, series, charmFileName string) (*ch
 }
 
 // addControllerApplication deploys and configures the controller application.
-func addControllerApplication(st *state.State, curl *charm.URL, origin corecharm.Origin, address, series string) (*state.Unit, error) {
+func addControllerApplication(
+	st *state.State, curl *charm.URL, origin corecharm.Origin, address, series string,
+	resourceSpecs []controllerCharmResource,
+) (*state.Unit, error) {
 	ch, err := st.Charm(curl)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+
+	resources, err := resolveControllerCharmResources(st, ch, origin, resourceSpecs)
+	if err != nil {
+		return nil, errors.Annotate(err, "resolving controller charm resources")
+	}
+
 	cfg := charm.Settings{
 		"is-juju": true,
 	}
@@ -298,6 +347,7 @@ func addControllerApplication(st *state.State, curl *charm.URL, origin corecharm
 		CharmConfig:       cfg,
 		ApplicationConfig: appCfg,
 		NumUnits:          1,
+		Resources:         resources,
 	})
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -0,0 +1,103 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package agent
+
+import (
+	"github.com/juju/charm/v9"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	corecharm "github.com/juju/juju/core/charm"
+)
+
+type controllerCharmChannelSuite struct{}
+
+var _ = gc.Suite(&controllerCharmChannelSuite{})
+
+var channelShapeTests = []struct {
+	about   string
+	channel string
+	track   string
+	risk    string
+	branch  string
+	err     string
+}{
+	{
+		about:   "risk only",
+		channel: "stable",
+		risk:    "stable",
+	},
+	{
+		about:   "track and risk",
+		channel: "2.9/stable",
+		track:   "2.9",
+		risk:    "stable",
+	},
+	{
+		about:   "track, risk and branch",
+		channel: "latest/edge/hotfix-x",
+		track:   "latest",
+		risk:    "edge",
+		branch:  "hotfix-x",
+	},
+	{
+		about:   "invalid risk",
+		channel: "2.9/bogus",
+		err:     `channel is malformed and has too many components ".*"|risk in channel "2.9/bogus" not valid`,
+	},
+	{
+		about:   "too many components",
+		channel: "2.9/stable/hotfix-x/extra",
+		err:     `channel is malformed and has too many components ".*"`,
+	},
+	{
+		about:   "empty",
+		channel: "",
+		err:     `channel cannot be empty`,
+	},
+}
+
+func (s *controllerCharmChannelSuite) TestChannelShapes(c *gc.C) {
+	for i, t := range channelShapeTests {
+		c.Logf("test %d: %s", i, t.about)
+		ch, err := charm.ParseChannel(t.channel)
+		if t.err != "" {
+			c.Check(err, gc.ErrorMatches, t.err)
+			continue
+		}
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(ch.Track, gc.Equals, t.track)
+		c.Check(ch.Risk.String(), gc.Equals, t.risk)
+		c.Check(ch.Branch, gc.Equals, t.branch)
+	}
+}
+
+// TestStoreControllerCharmOrigin drives storeControllerCharmOrigin, the
+// channel/revision handling populateStoreControllerCharm resolves against,
+// with the same shape permutations as TestChannelShapes, checking the
+// resulting origin and curl rather than just the underlying parser.
+func (s *controllerCharmChannelSuite) TestStoreControllerCharmOrigin(c *gc.C) {
+	for i, t := range channelShapeTests {
+		c.Logf("test %d: %s", i, t.about)
+		curl, origin, err := storeControllerCharmOrigin(t.channel, -1, "amd64")
+		if t.err != "" {
+			c.Check(err, gc.ErrorMatches, `parsing controller charm channel ".*": (?:`+t.err+`)`)
+			continue
+		}
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(curl.String(), gc.Equals, "ch:juju-controller")
+		c.Check(origin.Source, gc.Equals, corecharm.CharmHub)
+		c.Check(origin.Platform.Architecture, gc.Equals, "amd64")
+		c.Assert(origin.Channel, gc.NotNil)
+		c.Check(origin.Channel.Track, gc.Equals, t.track)
+		c.Check(origin.Channel.Risk.String(), gc.Equals, t.risk)
+		c.Check(origin.Channel.Branch, gc.Equals, t.branch)
+	}
+}
+
+func (s *controllerCharmChannelSuite) TestStoreControllerCharmOriginRevision(c *gc.C) {
+	curl, _, err := storeControllerCharmOrigin("stable", 42, "amd64")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(curl.Revision, gc.Equals, 42)
+}
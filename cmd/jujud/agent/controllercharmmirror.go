@@ -0,0 +1,211 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package agent
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/juju/charm/v9"
+	"github.com/juju/errors"
+	"gopkg.in/yaml.v2"
+
+	"github.com/juju/juju/apiserver"
+	corecharm "github.com/juju/juju/core/charm"
+	"github.com/juju/juju/state"
+)
+
+// mirrorIndexEntry describes a single controller charm revision available
+// from a local Charmhub mirror, similar to an image-metadata simplestreams
+// entry.
+type mirrorIndexEntry struct {
+	Name     string `json:"name" yaml:"name"`
+	Track    string `json:"track" yaml:"track"`
+	Risk     string `json:"risk" yaml:"risk"`
+	Revision int    `json:"revision" yaml:"revision"`
+	Arch     string `json:"arch" yaml:"arch"`
+	Series   string `json:"series" yaml:"series"`
+	File     string `json:"file" yaml:"file"`
+	SHA256   string `json:"sha256" yaml:"sha256"`
+}
+
+// mirrorIndex is the top level document of a Charmhub mirror, mapping
+// charm tuples onto the archives that satisfy them.
+type mirrorIndex struct {
+	Charms []mirrorIndexEntry `json:"charms" yaml:"charms"`
+}
+
+// mirrorControllerCharmRepo resolves and downloads the controller charm
+// from a local directory (or file:// URL) laid out as a Charmhub mirror.
+// It satisfies enough of corecharm.Repository to be used in place of the
+// real charmhub-backed repository during bootstrap.
+type mirrorControllerCharmRepo struct {
+	root  string
+	index mirrorIndex
+}
+
+// newMirrorControllerCharmRepo reads the mirror's index file from root (a
+// directory path or file:// URL).
+func newMirrorControllerCharmRepo(root string) (*mirrorControllerCharmRepo, error) {
+	root = strings.TrimPrefix(root, "file://")
+	index, err := readMirrorIndex(root)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &mirrorControllerCharmRepo{root: root, index: index}, nil
+}
+
+// readMirrorIndex looks for a JSON or YAML index file in root.
+func readMirrorIndex(root string) (mirrorIndex, error) {
+	for _, name := range []string{"index.json", "index.yaml", "index.yml"} {
+		path := filepath.Join(root, name)
+		data, err := ioutil.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return mirrorIndex{}, errors.Trace(err)
+		}
+		var index mirrorIndex
+		if strings.HasSuffix(name, ".json") {
+			err = json.Unmarshal(data, &index)
+		} else {
+			err = yaml.Unmarshal(data, &index)
+		}
+		return index, errors.Trace(err)
+	}
+	return mirrorIndex{}, errors.NotFoundf("mirror index in %q", root)
+}
+
+// ResolveWithPreferredChannel implements enough of corecharm.Repository to
+// let deployControllerCharm resolve the controller charm from the mirror.
+func (r *mirrorControllerCharmRepo) ResolveWithPreferredChannel(
+	curl *charm.URL, origin corecharm.Origin, _ []string,
+) (*charm.URL, corecharm.Origin, []string, error) {
+	entry, err := r.findEntry(curl.Name, curl.Revision, origin)
+	if err != nil {
+		return nil, corecharm.Origin{}, nil, errors.Trace(err)
+	}
+	resolved := curl.WithSeries(entry.Series).WithRevision(entry.Revision)
+	origin.Platform.Series = entry.Series
+	return resolved, origin, []string{entry.Series}, nil
+}
+
+// DownloadCharm copies the charm archive satisfying curl/origin to
+// destPath, verifying its digest against the mirror index.
+func (r *mirrorControllerCharmRepo) DownloadCharm(curl *charm.URL, origin corecharm.Origin, destPath string) (*charm.CharmArchive, error) {
+	entry, err := r.findEntry(curl.Name, curl.Revision, origin)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := copyAndVerify(filepath.Join(r.root, entry.File), destPath, entry.SHA256); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return charm.ReadCharmArchive(destPath)
+}
+
+// findEntry returns the mirror index entry matching name and, when set,
+// the requested revision, architecture and channel track/risk. revision
+// values below zero mean "no specific revision requested".
+func (r *mirrorControllerCharmRepo) findEntry(name string, revision int, origin corecharm.Origin) (mirrorIndexEntry, error) {
+	for _, e := range r.index.Charms {
+		if e.Name != name {
+			continue
+		}
+		if e.Arch != "" && e.Arch != origin.Platform.Architecture {
+			continue
+		}
+		if revision >= 0 && e.Revision != revision {
+			continue
+		}
+		if origin.Channel != nil && e.Track != "" && e.Track != origin.Channel.Track {
+			continue
+		}
+		if origin.Channel != nil && e.Risk != "" && e.Risk != origin.Channel.Risk.String() {
+			continue
+		}
+		return e, nil
+	}
+	return mirrorIndexEntry{}, errors.NotFoundf("controller charm %q in mirror", name)
+}
+
+func copyAndVerify(src, dst, expectSHA256 string) error {
+	data, err := ioutil.ReadFile(src)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if expectSHA256 != "" {
+		if sum := fmt.Sprintf("%x", sha256.Sum256(data)); sum != expectSHA256 {
+			return errors.Errorf("sha256 mismatch for %q: expected %s, got %s", src, expectSHA256, sum)
+		}
+	}
+	return ioutil.WriteFile(dst, data, 0644)
+}
+
+// populateMirrorControllerCharm downloads and stores the controller charm
+// from a local Charmhub mirror, for fully offline controller bootstraps.
+// charmChannel and charmRevision are honored the same way as the charmhub
+// source: a negative charmRevision means "no specific revision requested".
+func populateMirrorControllerCharm(st *state.State, mirrorPath, charmChannel string, charmRevision int, series, arch string) (*charm.URL, *corecharm.Origin, error) {
+	repo, err := newMirrorControllerCharmRepo(mirrorPath)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+
+	channel, err := parseControllerCharmChannel(charmChannel)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+
+	curl := charm.MustParseURL(controllerCharmURL)
+	if charmRevision >= 0 {
+		curl = curl.WithRevision(charmRevision)
+	}
+	origin := corecharm.Origin{
+		Source:  corecharm.CharmHub,
+		Type:    "charm",
+		Channel: &channel,
+		Platform: corecharm.Platform{
+			Architecture: arch,
+			Series:       series,
+		},
+	}
+	curl, origin, _, err = repo.ResolveWithPreferredChannel(curl, origin, nil)
+	if err != nil {
+		return nil, nil, errors.Annotatef(err, "resolving %q from mirror", controllerCharmURL)
+	}
+
+	tmpFile, err := ioutil.TempFile("", "controller-charm-mirror-*.charm")
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	tmpFile.Close()
+	defer os.Remove(tmpFile.Name())
+
+	archive, err := repo.DownloadCharm(curl, origin, tmpFile.Name())
+	if err != nil {
+		return nil, nil, errors.Annotate(err, "downloading controller charm from mirror")
+	}
+
+	reservedCurl, err := st.PrepareLocalCharmUpload(&charm.URL{
+		Schema:   charm.Local.String(),
+		Name:     archive.Meta().Name,
+		Revision: archive.Revision(),
+		Series:   series,
+	})
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	if err := apiserver.RepackageAndUploadCharm(st, archive, reservedCurl); err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+
+	logger.Debugf("Successfully deployed Juju controller charm from local mirror %q", mirrorPath)
+	return reservedCurl, &origin, nil
+}
@@ -0,0 +1,215 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package agent
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/juju/charm/v9/resource"
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/charmhub"
+	corecharm "github.com/juju/juju/core/charm"
+	"github.com/juju/juju/environs/bootstrap"
+	"github.com/juju/juju/state"
+)
+
+// newResourceClient is patched for testing.
+var newResourceClient = func(logger charmhub.Logger) (*charmhub.ResourceClient, error) {
+	return charmhub.NewDefaultResourceClient(charmhub.DefaultHTTPTransport(logger), logger)
+}
+
+// controllerCharmResource describes a single --controller-charm-resource
+// flag value: either a local file to upload, or a specific revision to
+// fetch from charmhub.
+type controllerCharmResource struct {
+	Name     string
+	Path     string
+	Revision int
+}
+
+// parseControllerCharmResource parses a flag value of the form
+// "name=path[,revision=N]".
+func parseControllerCharmResource(value string) (controllerCharmResource, error) {
+	nameValue := strings.SplitN(value, "=", 2)
+	if len(nameValue) != 2 || nameValue[0] == "" {
+		return controllerCharmResource{}, errors.Errorf(
+			"expected name=path[,revision=N], got %q", value,
+		)
+	}
+	result := controllerCharmResource{Name: nameValue[0], Revision: -1}
+
+	parts := strings.Split(nameValue[1], ",")
+	result.Path = parts[0]
+	for _, extra := range parts[1:] {
+		kv := strings.SplitN(extra, "=", 2)
+		if len(kv) != 2 || kv[0] != "revision" {
+			return controllerCharmResource{}, errors.Errorf("unexpected resource option %q", extra)
+		}
+		rev, err := strconv.Atoi(kv[1])
+		if err != nil {
+			return controllerCharmResource{}, errors.Errorf("invalid resource revision %q", kv[1])
+		}
+		result.Path = ""
+		result.Revision = rev
+	}
+	return result, nil
+}
+
+// resolveControllerCharmResources validates the requested resources
+// against the controller charm's metadata, uploads any local files
+// through the state resource store, and resolves store revisions for the
+// rest, returning a map of resource name to the revision to attach.
+func resolveControllerCharmResources(
+	st *state.State, ch *state.Charm, origin corecharm.Origin, specs []controllerCharmResource,
+) (map[string]int, error) {
+	meta := ch.Meta().Resources
+	if err := validateControllerCharmResourceNames(meta, ch.Meta().Name, specs); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	result := make(map[string]int, len(specs))
+	for _, spec := range specs {
+		if spec.Path != "" {
+			rev, err := uploadLocalControllerResource(st, meta[spec.Name], spec.Path)
+			if err != nil {
+				return nil, errors.Annotatef(err, "uploading resource %q", spec.Name)
+			}
+			result[spec.Name] = rev
+			continue
+		}
+
+		rev, err := resolveStoreControllerResource(st, meta[spec.Name], origin, spec.Name, spec.Revision)
+		if err != nil {
+			return nil, errors.Annotatef(err, "resolving resource %q", spec.Name)
+		}
+		result[spec.Name] = rev
+	}
+	return result, nil
+}
+
+// validateControllerCharmResourceNames checks that every requested resource
+// spec names a resource the controller charm actually declares, factored
+// out of resolveControllerCharmResources so it can be exercised without a
+// state.Charm.
+func validateControllerCharmResourceNames(meta map[string]resource.Meta, charmName string, specs []controllerCharmResource) error {
+	for _, spec := range specs {
+		if _, ok := meta[spec.Name]; !ok {
+			return errors.NotValidf("resource %q for controller charm %q", spec.Name, charmName)
+		}
+	}
+	return nil
+}
+
+// uploadLocalControllerResource reads a local file and stores it as the
+// named resource for the controller application, returning its revision.
+func uploadLocalControllerResource(st *state.State, meta resource.Meta, path string) (int, error) {
+	res, f, err := readLocalControllerResource(meta, path)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	defer f.Close()
+
+	stored, err := st.Resources().SetResource(
+		bootstrap.ControllerApplicationName, "", res, f,
+	)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	return stored.Revision, nil
+}
+
+// readLocalControllerResource opens path and fingerprints it, returning a
+// resource.Resource ready to pass to a resource store alongside the open
+// file positioned at its start. It is factored out of
+// uploadLocalControllerResource so the local-file handling can be
+// exercised without a state.State.
+func readLocalControllerResource(meta resource.Meta, path string) (resource.Resource, *os.File, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return resource.Resource{}, nil, errors.Trace(err)
+	}
+
+	fp, err := resource.GenerateFingerprint(f)
+	if err != nil {
+		f.Close()
+		return resource.Resource{}, nil, errors.Trace(err)
+	}
+	if _, err := f.Seek(0, os.SEEK_SET); err != nil {
+		f.Close()
+		return resource.Resource{}, nil, errors.Trace(err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return resource.Resource{}, nil, errors.Trace(err)
+	}
+
+	return resource.Resource{
+		Meta:        meta,
+		Origin:      resource.OriginUpload,
+		Size:        info.Size(),
+		Fingerprint: fp,
+	}, f, nil
+}
+
+// resolveStoreControllerResource resolves a charmhub resource revision for
+// the controller charm, downloads it and stores it through state's resource
+// store the same way uploadLocalControllerResource does for a local file.
+// An explicit revision is required: the mirror and charmhub
+// resource-listing APIs don't expose a stable "latest" lookup outside of a
+// full refresh request.
+func resolveStoreControllerResource(st *state.State, meta resource.Meta, origin corecharm.Origin, name string, revision int) (int, error) {
+	if revision < 0 {
+		return 0, errors.NotValidf("resource %q without an explicit --controller-charm-resource revision", name)
+	}
+
+	tmpPath, err := downloadStoreControllerResource(origin, name, revision)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	defer os.Remove(tmpPath)
+
+	return uploadLocalControllerResource(st, meta, tmpPath)
+}
+
+// downloadStoreControllerResource resolves revision of the named resource
+// against charmhub and downloads it to a temporary file, returning its
+// path. It is factored out of resolveStoreControllerResource so the
+// charmhub resolve/download plumbing can be exercised without a
+// state.State.
+func downloadStoreControllerResource(origin corecharm.Origin, name string, revision int) (string, error) {
+	client, err := newResourceClient(logger)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	ctx := context.Background()
+	rev, err := client.ResourceRevision(ctx, origin.ID, name, revision)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+
+	tmpFile, err := ioutil.TempFile("", "controller-charm-resource-*")
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	tmpFile.Close()
+
+	f, err := os.OpenFile(tmpFile.Name(), os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		os.Remove(tmpFile.Name())
+		return "", errors.Trace(err)
+	}
+	downloadErr := client.Download(ctx, rev.DownloadURL, f)
+	f.Close()
+	if downloadErr != nil {
+		os.Remove(tmpFile.Name())
+		return "", errors.Annotatef(downloadErr, "downloading resource %q revision %d", name, revision)
+	}
+	return tmpFile.Name(), nil
+}
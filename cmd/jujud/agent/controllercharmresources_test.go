@@ -0,0 +1,119 @@
+// Copyright 2021 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package agent
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+
+	"github.com/juju/charm/v9/resource"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/charmhub"
+	"github.com/juju/juju/charmhub/path"
+	corecharm "github.com/juju/juju/core/charm"
+)
+
+type controllerCharmResourceSuite struct{}
+
+var _ = gc.Suite(&controllerCharmResourceSuite{})
+
+func (s *controllerCharmResourceSuite) TestParseLocalFile(c *gc.C) {
+	res, err := parseControllerCharmResource("dashboard=/tmp/dashboard.tar.gz")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(res, gc.Equals, controllerCharmResource{Name: "dashboard", Path: "/tmp/dashboard.tar.gz", Revision: -1})
+}
+
+func (s *controllerCharmResourceSuite) TestParseStoreRevision(c *gc.C) {
+	res, err := parseControllerCharmResource("dashboard=ignored,revision=3")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(res, gc.Equals, controllerCharmResource{Name: "dashboard", Path: "", Revision: 3})
+}
+
+func (s *controllerCharmResourceSuite) TestParseInvalid(c *gc.C) {
+	_, err := parseControllerCharmResource("no-equals-sign")
+	c.Assert(err, gc.ErrorMatches, `expected name=path\[,revision=N\], got ".*"`)
+}
+
+func (s *controllerCharmResourceSuite) TestResolveStoreRequiresRevision(c *gc.C) {
+	_, err := resolveStoreControllerResource(nil, resource.Meta{}, corecharm.Origin{}, "dashboard", -1)
+	c.Assert(err, gc.ErrorMatches, `resource "dashboard" without an explicit --controller-charm-resource revision not valid`)
+}
+
+func (s *controllerCharmResourceSuite) TestValidateResourceNamesOK(c *gc.C) {
+	meta := map[string]resource.Meta{"dashboard": {Name: "dashboard"}}
+	specs := []controllerCharmResource{{Name: "dashboard", Path: "/tmp/dashboard.tar.gz", Revision: -1}}
+	err := validateControllerCharmResourceNames(meta, "juju-controller", specs)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *controllerCharmResourceSuite) TestValidateResourceNamesRejectsUnknown(c *gc.C) {
+	meta := map[string]resource.Meta{"dashboard": {Name: "dashboard"}}
+	specs := []controllerCharmResource{{Name: "metrics", Revision: 3}}
+	err := validateControllerCharmResourceNames(meta, "juju-controller", specs)
+	c.Assert(err, gc.ErrorMatches, `resource "metrics" for controller charm "juju-controller" not valid`)
+}
+
+func (s *controllerCharmResourceSuite) TestReadLocalControllerResource(c *gc.C) {
+	dir := c.MkDir()
+	path := filepath.Join(dir, "dashboard.tar.gz")
+	c.Assert(ioutil.WriteFile(path, []byte("archive contents"), 0644), jc.ErrorIsNil)
+
+	meta := resource.Meta{Name: "dashboard"}
+	res, f, err := readLocalControllerResource(meta, path)
+	c.Assert(err, jc.ErrorIsNil)
+	defer f.Close()
+
+	c.Check(res.Meta, gc.Equals, meta)
+	c.Check(res.Origin, gc.Equals, resource.OriginUpload)
+	c.Check(res.Size, gc.Equals, int64(len("archive contents")))
+
+	// The returned file is seeked back to the start, ready for a resource
+	// store to read its contents after fingerprinting.
+	contents, err := ioutil.ReadAll(f)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(string(contents), gc.Equals, "archive contents")
+}
+
+func (s *controllerCharmResourceSuite) TestDownloadStoreControllerResource(c *gc.C) {
+	const wantContent = "archive contents"
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/dl/dashboard", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, wantContent)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	mux.HandleFunc("/v2/charms/ch123/resources/dashboard/revisions/3", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"revision": {"revision": 3, "download-url": %q}}`, server.URL+"/dl/dashboard")
+	})
+
+	root, err := path.MakePath(server.URL)
+	c.Assert(err, jc.ErrorIsNil)
+	restClient := charmhub.NewHTTPRESTClient(http.DefaultTransport)
+
+	original := newResourceClient
+	defer func() { newResourceClient = original }()
+	newResourceClient = func(l charmhub.Logger) (*charmhub.ResourceClient, error) {
+		return charmhub.NewResourceClient(root, restClient, http.DefaultTransport, l), nil
+	}
+
+	tmpPath, err := downloadStoreControllerResource(corecharm.Origin{ID: "ch123"}, "dashboard", 3)
+	c.Assert(err, jc.ErrorIsNil)
+	defer os.Remove(tmpPath)
+
+	got, err := ioutil.ReadFile(tmpPath)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(string(got), gc.Equals, wantContent)
+}
+
+func (s *controllerCharmResourceSuite) TestReadLocalControllerResourceMissingFile(c *gc.C) {
+	_, _, err := readLocalControllerResource(resource.Meta{Name: "dashboard"}, filepath.Join(c.MkDir(), "missing"))
+	c.Assert(err, gc.ErrorMatches, `.*no such file or directory`)
+}